@@ -0,0 +1,150 @@
+//go:build safeish_checkptr
+
+package safeish
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// checkAlign panics if ptr isn't aligned to the alignment required by DstE,
+// mirroring the runtime's checkptrAlignment diagnostic.
+func checkAlign[DstE any](ptr unsafe.Pointer) {
+	align := unsafe.Alignof(*new(DstE))
+	if uintptr(ptr)%align != 0 {
+		panic(fmt.Sprintf(
+			"safeish: checkptr: pointer %#x is not aligned to the %d-byte boundary required by %T",
+			ptr, align, *new(DstE)))
+	}
+}
+
+// Cast casts x from type Src to type Dst. It uses generics to provide a
+// syntactic alternative to the common unsafe.Pointer conversion pattern.
+//
+// Example:
+//
+//	var x Foo
+//
+//	_ = Cast[Bar](x)
+//	// the above is identical to the below
+//	_ = *(*Bar)(unsafe.Pointer(&x))
+//
+// Building with the safeish_checkptr build tag enables a runtime check that
+// the conversion doesn't violate Dst's alignment requirements, similar to
+// -d=checkptr.
+func Cast[Dst, Src any](x Src) Dst {
+	ptr := unsafe.Pointer(&x)
+	checkAlign[Dst](ptr)
+	return *(*Dst)(ptr)
+}
+
+// SliceCast casts a slice of underlying type []SrcE to a slice of underlying
+// type []DstE, automatically adjusting the length and capacity based on the
+// ratio of sizeof(SrcE) to sizeof(DstE). sizeof(DstE) may be both larger or
+// smaller than sizeof(SrcE).
+//
+// The ratio is expected to be integer, but non-integer ratios will not cause
+// invalid memory accesses.
+//
+// The type parameters are ordered so that at most the first one has to be
+// provided explicitly.
+//
+// SliceCast is fully inlinable when built without the safeish_checkptr build
+// tag. The checks added by this build make it too costly to inline.
+//
+// Building with the safeish_checkptr build tag enables runtime checks of
+// DstE's alignment and of the source capacity, similar to -d=checkptr.
+func SliceCast[Dst ~[]DstE, Src ~[]SrcE, DstE, SrcE any](x Src) Dst {
+	type sliceHeader struct {
+		data unsafe.Pointer
+		len  int
+		cap  int
+	}
+
+	if cap(x) == 0 {
+		return nil
+	}
+
+	ptrDst := (*sliceHeader)(unsafe.Pointer(&x)).data
+	checkAlign[DstE](ptrDst)
+
+	sizeSrc := unsafe.Sizeof(*new(SrcE))
+	sizeDst := unsafe.Sizeof(*new(DstE))
+
+	if srcBytes := cap(x) * int(sizeSrc); srcBytes < int(sizeDst) {
+		panic(fmt.Sprintf(
+			"safeish: checkptr: SliceCast: slice has capacity of %d bytes, but a single %T is %d bytes (%d bytes would be truncated)",
+			srcBytes, *new(DstE), sizeDst, srcBytes%int(sizeDst)))
+	}
+
+	if sizeSrc >= sizeDst {
+		return *(*Dst)(unsafe.Pointer(&sliceHeader{
+			data: ptrDst,
+			len:  len(x) * int(sizeSrc/sizeDst),
+			cap:  cap(x) * int(sizeSrc/sizeDst),
+		}))
+	} else {
+		return *(*Dst)(unsafe.Pointer(&sliceHeader{
+			data: ptrDst,
+			len:  len(x) / int(sizeDst/sizeSrc),
+			cap:  cap(x) / int(sizeDst/sizeSrc),
+		}))
+	}
+}
+
+// Index provides unsafe slice indexing without the language's bounds checks.
+// Built without the safeish_checkptr build tag, this function has absolutely
+// no safety checks.
+//
+// Building with the safeish_checkptr build tag enables runtime checks that
+// the computed offset is in bounds and that the resulting pointer satisfies
+// E's alignment requirements, similar to -d=checkptr.
+func Index[E any, S ~[]E, Int constraints.Integer](ptr S, idx Int) *E {
+	size := unsafe.Sizeof(*new(E))
+	offset := size * uintptr(idx)
+
+	if limit := uintptr(cap(ptr)) * size; offset >= limit {
+		panic(fmt.Sprintf(
+			"safeish: checkptr: Index: offset %d is out of bounds for a %T with capacity %d (%d bytes)",
+			offset, *new(E), cap(ptr), limit))
+	}
+
+	p := unsafe.Add(unsafe.Pointer(&ptr[0]), offset)
+	checkAlign[E](p)
+	return (*E)(p)
+}
+
+// SliceCastPtr casts a slice of underlying type []SrcE to a pointer of
+// underlying type *DstE to the slice's first element, or nil if the slice's
+// capacity is 0. It ensures that the pointer doesn't extend past the end of the
+// slice.
+//
+// Building with the safeish_checkptr build tag enables an additional runtime
+// check of DstE's alignment, similar to -d=checkptr.
+func SliceCastPtr[Dst ~*DstE, Src ~[]SrcE, DstE, SrcE any](x Src) Dst {
+	if cap(x) == 0 {
+		return nil
+	}
+	type sliceHeader struct {
+		data unsafe.Pointer
+		len  int
+		cap  int
+	}
+
+	sizeSrc := unsafe.Sizeof(*new(SrcE))
+	sizeDst := unsafe.Sizeof(*new(DstE))
+
+	if sizeSrc != sizeDst {
+		if sz := int(sizeSrc) * cap(x); sz < int(sizeDst) {
+			panic(
+				fmt.Sprintf("slice has capacity of %d bytes, but a single %T is %d bytes",
+					sz, *new(DstE), sizeDst))
+		}
+	}
+
+	ptrDst := (*sliceHeader)(unsafe.Pointer(&x)).data
+	checkAlign[DstE](ptrDst)
+	return Dst(ptrDst)
+}
@@ -0,0 +1,38 @@
+//go:build safeish_checkptr
+
+package safeish
+
+import "testing"
+
+func TestCheckAlignPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a misaligned pointer")
+		}
+	}()
+
+	b := make([]byte, 16)
+	_ = SliceCast[[]uint64](b[1:9])
+}
+
+func TestSliceCastChecksCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an undersized source slice")
+		}
+	}()
+
+	b := make([]byte, 4, 4)
+	_ = SliceCast[[]uint64](b)
+}
+
+func TestIndexChecksBounds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an out-of-bounds index")
+		}
+	}()
+
+	s := make([]uint32, 2, 4)
+	_ = Index(s, 4)
+}
@@ -2,80 +2,113 @@
 package safeish
 
 import (
-	"fmt"
 	"strings"
 	"unsafe"
 
 	"golang.org/x/exp/constraints"
 )
 
-// Cast casts x from type Src to type Dst. It uses generics to provide a
-// syntactic alternative to the common unsafe.Pointer conversion pattern.
+// StringToBytes returns the underlying bytes of s as a []byte, without
+// copying. The returned slice has len == cap, so appending to it will
+// always reallocate instead of accidentally writing into the string's
+// storage. The result must not be mutated, as string data may be shared
+// and is assumed to be immutable.
 //
-// Example:
-//
-//	var x Foo
-//
-//	_ = Cast[Bar](x)
-//	// the above is identical to the below
-//	_ = *(*Bar)(unsafe.Pointer(&x))
-func Cast[Dst, Src any](x Src) Dst {
-	return *(*Dst)(unsafe.Pointer(&x))
+// StringToBytes is fully inlinable.
+func StringToBytes(s string) []byte {
+	// We don't use our Cast helper in this function because it increases the
+	// function complexity, making inlining more difficult.
+
+	type stringHeader struct {
+		data unsafe.Pointer
+		len  int
+	}
+	type sliceHeader struct {
+		data unsafe.Pointer
+		len  int
+		cap  int
+	}
+
+	if len(s) == 0 {
+		return nil
+	}
+
+	ptrSrc := (*stringHeader)(unsafe.Pointer(&s)).data
+
+	return *(*[]byte)(unsafe.Pointer(&sliceHeader{
+		data: ptrSrc,
+		len:  len(s),
+		cap:  len(s),
+	}))
 }
 
-// SliceCast casts a slice of underlying type []SrcE to a slice of underlying
-// type []DstE, automatically adjusting the length and capacity based on the
-// ratio of sizeof(SrcE) to sizeof(DstE). sizeof(DstE) may be both larger or
-// smaller than sizeof(SrcE).
+// BytesToString returns the bytes in b interpreted as a string, without
+// copying. As with the standard library's strings.Builder, the caller must
+// not modify b for as long as the returned string is in use.
 //
-// The ratio is expected to be integer, but non-integer ratios will not cause
-// invalid memory accesses.
+// BytesToString is fully inlinable.
+func BytesToString(b []byte) string {
+	// We don't use our Cast helper in this function because it increases the
+	// function complexity, making inlining more difficult.
+
+	type sliceHeader struct {
+		data unsafe.Pointer
+		len  int
+		cap  int
+	}
+	type stringHeader struct {
+		data unsafe.Pointer
+		len  int
+	}
+
+	if len(b) == 0 {
+		return ""
+	}
+
+	ptrSrc := (*sliceHeader)(unsafe.Pointer(&b)).data
+
+	return *(*string)(unsafe.Pointer(&stringHeader{
+		data: ptrSrc,
+		len:  len(b),
+	}))
+}
+
+// StringCast casts a string to a slice of underlying type []DstE,
+// automatically computing the length based on sizeof(DstE). It is the
+// string-based counterpart to SliceCast, with SrcE fixed to byte.
 //
-// The type parameters are ordered so that at most the first one has to be
-// provided explicitly.
+// sizeof(DstE) is expected to evenly divide len(s), but a non-integer ratio
+// will not cause invalid memory accesses; the remainder is simply dropped.
 //
-// SliceCast is fully inlinable.
-func SliceCast[Dst ~[]DstE, Src ~[]SrcE, DstE, SrcE any](x Src) Dst {
+// StringCast is fully inlinable.
+func StringCast[Dst ~[]DstE, DstE any](s string) Dst {
 	// We don't use our Cast helper in this function because it increases the
 	// function complexity, making inlining more difficult.
 
+	type stringHeader struct {
+		data unsafe.Pointer
+		len  int
+	}
 	type sliceHeader struct {
 		data unsafe.Pointer
 		len  int
 		cap  int
 	}
 
-	if cap(x) == 0 {
+	if len(s) == 0 {
 		return nil
 	}
 
-	// This way of getting the pointer has lower inlining complexity than
-	// &x[:1][0]
-	ptrDst := (*sliceHeader)(unsafe.Pointer(&x)).data
+	ptrDst := (*stringHeader)(unsafe.Pointer(&s)).data
 
-	sizeSrc := unsafe.Sizeof(*new(SrcE))
 	sizeDst := unsafe.Sizeof(*new(DstE))
+	n := len(s) / int(sizeDst)
 
-	if sizeSrc >= sizeDst {
-		return *(*Dst)(unsafe.Pointer(&sliceHeader{
-			data: ptrDst,
-			len:  len(x) * int(sizeSrc/sizeDst),
-			cap:  cap(x) * int(sizeSrc/sizeDst),
-		}))
-	} else {
-		return *(*Dst)(unsafe.Pointer(&sliceHeader{
-			data: ptrDst,
-			len:  len(x) / int(sizeDst/sizeSrc),
-			cap:  cap(x) / int(sizeDst/sizeSrc),
-		}))
-	}
-}
-
-// Index provides unsafe slice indexing without bounds checks. This function has
-// absolutely no safety checks.
-func Index[E any, S ~[]E, Int constraints.Integer](ptr S, idx Int) *E {
-	offset := unsafe.Sizeof(*new(E)) * uintptr(idx)
-	return (*E)(unsafe.Add(unsafe.Pointer(&ptr[0]), offset))
+	return *(*Dst)(unsafe.Pointer(&sliceHeader{
+		data: ptrDst,
+		len:  n,
+		cap:  n,
+	}))
 }
 
 // AsBytes returns the underlying byte representation of the value pointed to by
@@ -84,6 +117,58 @@ func AsBytes[E any, T *E](ptr T) []byte {
 	return unsafe.Slice((*byte)(unsafe.Pointer(ptr)), unsafe.Sizeof(*ptr))
 }
 
+// SliceFromPtr builds a slice of length and capacity length from ptr. It
+// mirrors unsafe.Slice, with the one difference that, following Go 1.17's
+// treatment of unsafe.Slice, a length of 0 always returns nil, even if ptr
+// is nil.
+//
+// SliceFromPtr panics if length is negative or if int(length)*sizeof(E)
+// overflows uintptr, the same as unsafe.Slice.
+func SliceFromPtr[E any, Int constraints.Integer](ptr *E, length Int) []E {
+	if length == 0 {
+		return nil
+	}
+	return unsafe.Slice(ptr, length)
+}
+
+// SliceOfPtr builds a slice of the given length and capacity from ptr, for
+// when the usable capacity differs from the slice's length, such as slices
+// obtained from C or mmap'd memory. It returns nil if ptr is nil and
+// capacity is 0.
+//
+// SliceOfPtr panics if length is negative, greater than capacity, or if
+// int(capacity)*sizeof(E) overflows uintptr.
+func SliceOfPtr[E any](ptr *E, length, capacity int) []E {
+	return unsafe.Slice(ptr, capacity)[:length]
+}
+
+// SizeOf returns the size in bytes of a value of type T, the same as
+// unsafe.Sizeof(*new(T)). It is useful in generic code, where
+// unsafe.Sizeof cannot be applied directly to a type parameter.
+func SizeOf[T any]() uintptr {
+	return unsafe.Sizeof(*new(T))
+}
+
+// AlignOf returns the alignment in bytes of a value of type T, the same as
+// unsafe.Alignof(*new(T)). It is useful in generic code, where
+// unsafe.Alignof cannot be applied directly to a type parameter.
+func AlignOf[T any]() uintptr {
+	return unsafe.Alignof(*new(T))
+}
+
+// OffsetOf returns the offset in bytes, within a Struct value, of the field
+// returned by getter. getter must return a pointer to one of the direct or
+// nested fields of its argument; it is typically a function literal such as
+//
+//	safeish.OffsetOf(func(s *S) *uint32 { return &s.Field })
+//
+// OffsetOf exists because unsafe.Offsetof cannot be applied to a field of a
+// generic type parameter.
+func OffsetOf[Struct, Field any](getter func(*Struct) *Field) uintptr {
+	var zero Struct
+	return uintptr(unsafe.Pointer(getter(&zero))) - uintptr(unsafe.Pointer(&zero))
+}
+
 func FindNull(s *byte) int {
 	if s == nil {
 		return 0
@@ -113,38 +198,3 @@ func FindNull(s *byte) int {
 		safeLen = pageSize
 	}
 }
-
-// SliceCastPtr casts a slice of underlying type []SrcE to a pointer of
-// underlying type *DstE to the slice's first element, or nil if the slice's
-// capacity is 0. It ensures that the pointer doesn't extend past the end of the
-// slice.
-func SliceCastPtr[Dst ~*DstE, Src ~[]SrcE, DstE, SrcE any](x Src) Dst {
-	if cap(x) == 0 {
-		return nil
-	}
-	type sliceHeader struct {
-		data unsafe.Pointer
-		len  int
-		cap  int
-	}
-
-	sizeSrc := unsafe.Sizeof(*new(SrcE))
-	sizeDst := unsafe.Sizeof(*new(DstE))
-
-	if sizeSrc != sizeDst {
-		// This check gets eliminated by the compiler when the sizes match, but
-		// the inliner doesn't know that. GOEXPERIMENT=newinliner claims that
-		// this function is inlinable, but it doesn't actually get inlined.
-
-		if sz := int(sizeSrc) * cap(x); sz < int(sizeDst) {
-			panic(
-				fmt.Sprintf("slice has capacity of %d bytes, but a single %T is %d bytes",
-					sz, *new(DstE), sizeDst))
-		}
-	}
-
-	// This way of getting the pointer has lower inlining complexity than
-	// &x[:1][0]
-	ptrDst := (*sliceHeader)(unsafe.Pointer(&x)).data
-	return Dst(ptrDst)
-}
@@ -2,7 +2,11 @@ package safeish
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
 	"testing"
+	"unsafe"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -19,6 +23,60 @@ func ExampleSliceCast() {
 	// 4 7
 }
 
+func ExampleStringCast() {
+	type S struct {
+		A, B uint32
+	}
+
+	s := StringCast[[]S]("aaaabbbbccccdddd")
+	fmt.Println(len(s), cap(s))
+	// Output:
+	// 2 2
+}
+
+func TestStringToBytes(t *testing.T) {
+	if b := StringToBytes(""); b != nil {
+		t.Errorf("got %#v, want nil", b)
+	}
+
+	s := "hello world"
+	b := StringToBytes(s)
+	if len(b) != len(s) || cap(b) != len(s) {
+		t.Errorf("got len %d, cap %d, want len %d, cap %d", len(b), cap(b), len(s), len(s))
+	}
+	if string(b) != s {
+		t.Errorf("got %q, want %q", b, s)
+	}
+}
+
+func TestBytesToString(t *testing.T) {
+	if s := BytesToString(nil); s != "" {
+		t.Errorf("got %q, want empty string", s)
+	}
+
+	b := []byte("hello world")
+	s := BytesToString(b)
+	if s != string(b) {
+		t.Errorf("got %q, want %q", s, b)
+	}
+}
+
+// TestInlining makes sure that our zero-copy conversions stay cheap enough
+// for the compiler's inliner, matching the same low-complexity
+// sliceHeader/stringHeader pattern used by SliceCast.
+func TestInlining(t *testing.T) {
+	out, err := exec.Command("go", "test", "-gcflags=-m -m", "-run", "NONE", "-c", "-o", os.DevNull, ".").CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to build package: %s\n%s", err, out)
+	}
+
+	for _, fn := range []string{"StringToBytes", "BytesToString", "StringCast"} {
+		if !strings.Contains(string(out), "can inline "+fn) {
+			t.Errorf("%s is no longer inlinable:\n%s", fn, out)
+		}
+	}
+}
+
 func TestAsBytes(t *testing.T) {
 	type X struct {
 		A uint32
@@ -39,3 +97,77 @@ func TestAsBytes(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestSliceFromPtr(t *testing.T) {
+	if s := SliceFromPtr[byte](nil, 0); s != nil {
+		t.Errorf("got %#v, want nil", s)
+	}
+
+	x := [4]uint32{1, 2, 3, 4}
+	s := SliceFromPtr(&x[0], 4)
+	if diff := cmp.Diff([]uint32{1, 2, 3, 4}, s); diff != "" {
+		t.Error(diff)
+	}
+	if len(s) != 4 || cap(s) != 4 {
+		t.Errorf("got len %d, cap %d, want len 4, cap 4", len(s), cap(s))
+	}
+}
+
+func TestSliceOfPtr(t *testing.T) {
+	if s := SliceOfPtr[byte](nil, 0, 0); s != nil {
+		t.Errorf("got %#v, want nil", s)
+	}
+
+	x := [4]uint32{1, 2, 3, 4}
+	s := SliceOfPtr(&x[0], 2, 4)
+	if diff := cmp.Diff([]uint32{1, 2}, s); diff != "" {
+		t.Error(diff)
+	}
+	if len(s) != 2 || cap(s) != 4 {
+		t.Errorf("got len %d, cap %d, want len 2, cap 4", len(s), cap(s))
+	}
+}
+
+func TestSliceOfPtrPanicsOnLengthGreaterThanCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for length > capacity")
+		}
+	}()
+
+	var x uint32
+	_ = SliceOfPtr(&x, 5, 0)
+}
+
+func TestSizeOfAlignOf(t *testing.T) {
+	type X struct {
+		A uint32
+		B uint64
+		C uint32
+	}
+
+	if got, want := SizeOf[X](), unsafe.Sizeof(X{}); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if got, want := AlignOf[X](), unsafe.Alignof(X{}); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+func TestOffsetOf(t *testing.T) {
+	type X struct {
+		A uint32
+		B uint64
+		C uint32
+	}
+
+	if got, want := OffsetOf(func(x *X) *uint32 { return &x.A }), unsafe.Offsetof(X{}.A); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if got, want := OffsetOf(func(x *X) *uint64 { return &x.B }), unsafe.Offsetof(X{}.B); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+	if got, want := OffsetOf(func(x *X) *uint32 { return &x.C }), unsafe.Offsetof(X{}.C); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+package safeish
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// AlignmentError reports that a pointer wasn't aligned to the boundary
+// required by the type it was meant to be viewed as.
+type AlignmentError struct {
+	Type      string
+	Pointer   unsafe.Pointer
+	Alignment uintptr
+}
+
+func (e *AlignmentError) Error() string {
+	return fmt.Sprintf("safeish: %#x is not aligned to the %d-byte boundary required by %s",
+		e.Pointer, e.Alignment, e.Type)
+}
+
+// ViewOne views the first sizeof(T) bytes of b as a *T, without copying, and
+// returns the remaining bytes for further parsing. It returns an error
+// instead of a *T if b is too short to hold a T, or if b isn't aligned to
+// T's required alignment.
+//
+// The returned *T aliases b; b must not be modified for as long as the
+// pointer is in use.
+func ViewOne[T any](b []byte) (*T, []byte, error) {
+	size := SizeOf[T]()
+	if uintptr(len(b)) < size {
+		return nil, nil, fmt.Errorf("safeish: ViewOne: need %d bytes for a %T, got %d", size, *new(T), len(b))
+	}
+
+	ptr := unsafe.Pointer(unsafe.SliceData(b))
+	if align := AlignOf[T](); uintptr(ptr)%align != 0 {
+		return nil, nil, &AlignmentError{
+			Type:      fmt.Sprintf("%T", *new(T)),
+			Pointer:   ptr,
+			Alignment: align,
+		}
+	}
+
+	return (*T)(ptr), b[size:], nil
+}
+
+// ViewMany views the first n*sizeof(T) bytes of b as a []T, without copying,
+// and returns the remaining bytes for further parsing. It returns an error
+// instead of a []T if b is too short to hold n values of T, or if b isn't
+// aligned to T's required alignment.
+//
+// The returned []T aliases b; b must not be modified for as long as the
+// slice is in use.
+func ViewMany[T any](b []byte, n int) ([]T, []byte, error) {
+	if n == 0 {
+		return nil, b, nil
+	}
+
+	size := SizeOf[T]()
+	needed := size * uintptr(n)
+	if uintptr(len(b)) < needed {
+		return nil, nil, fmt.Errorf("safeish: ViewMany: need %d bytes for %d %Ts, got %d", needed, n, *new(T), len(b))
+	}
+
+	ptr := unsafe.Pointer(unsafe.SliceData(b))
+	if align := AlignOf[T](); uintptr(ptr)%align != 0 {
+		return nil, nil, &AlignmentError{
+			Type:      fmt.Sprintf("%T", *new(T)),
+			Pointer:   ptr,
+			Alignment: align,
+		}
+	}
+
+	return SliceFromPtr((*T)(ptr), n), b[needed:], nil
+}
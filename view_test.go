@@ -0,0 +1,71 @@
+package safeish
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestViewOne(t *testing.T) {
+	type Header struct {
+		Magic   uint32
+		Version uint32
+	}
+
+	b := []byte{1, 0, 0, 0, 2, 0, 0, 0, 'r', 'e', 's', 't'}
+	h, rest, err := ViewOne[Header](b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Magic != 1 || h.Version != 2 {
+		t.Errorf("got %+v, want {Magic:1 Version:2}", h)
+	}
+	if string(rest) != "rest" {
+		t.Errorf("got rest %q, want %q", rest, "rest")
+	}
+
+	if _, _, err := ViewOne[Header](b[:4]); err == nil {
+		t.Error("expected an error for a short buffer")
+	}
+
+	if _, _, err := ViewOne[Header](b[1:]); err == nil {
+		t.Error("expected an error for a misaligned buffer")
+	} else {
+		var alignErr *AlignmentError
+		if !errors.As(err, &alignErr) {
+			t.Errorf("got %T, want *AlignmentError", err)
+		}
+	}
+}
+
+func TestViewMany(t *testing.T) {
+	b := []byte{1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 'r', 'e', 's', 't'}
+	s, rest, err := ViewMany[uint32](b, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]uint32{1, 2, 3}, s); diff != "" {
+		t.Error(diff)
+	}
+	if string(rest) != "rest" {
+		t.Errorf("got rest %q, want %q", rest, "rest")
+	}
+
+	if s, rest, err := ViewMany[uint32](b, 0); err != nil || s != nil || string(rest) != string(b) {
+		t.Errorf("got (%v, %q, %v), want (nil, %q, nil)", s, rest, err, b)
+	}
+
+	if _, _, err := ViewMany[uint32](b, 5); err == nil {
+		t.Error("expected an error for a short buffer")
+	}
+
+	if _, _, err := ViewMany[uint32](b[1:], 2); err == nil {
+		t.Error("expected an error for a misaligned buffer")
+	} else {
+		var alignErr *AlignmentError
+		if !errors.As(err, &alignErr) {
+			t.Errorf("got %T, want *AlignmentError", err)
+		}
+	}
+}